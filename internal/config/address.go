@@ -0,0 +1,53 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import "strings"
+
+// NormalizeServerAddress normalizes a server address for matching against
+// credHelpers and auths keys: it strips a leading "https://" or "http://"
+// scheme, lowercases the host (and port) component, and trims a trailing
+// slash. Any path component is left as-is.
+func NormalizeServerAddress(serverAddress string) string {
+	serverAddress = strings.TrimSuffix(serverAddress, "/")
+	switch {
+	case strings.HasPrefix(serverAddress, "https://"):
+		serverAddress = serverAddress[len("https://"):]
+	case strings.HasPrefix(serverAddress, "http://"):
+		serverAddress = serverAddress[len("http://"):]
+	}
+
+	host, path, hasPath := strings.Cut(serverAddress, "/")
+	host = strings.ToLower(host)
+	if !hasPath {
+		return host
+	}
+	return host + "/" + path
+}
+
+// PrefixCandidates returns the path prefixes of a normalized server
+// address, from longest to shortest, e.g. "ghcr.io/myorg/repo" yields
+// ["ghcr.io/myorg/repo", "ghcr.io/myorg", "ghcr.io"].
+func PrefixCandidates(serverAddress string) []string {
+	candidates := []string{serverAddress}
+	for i := len(serverAddress) - 1; i >= 0; i-- {
+		if serverAddress[i] == '/' {
+			serverAddress = serverAddress[:i]
+			candidates = append(candidates, serverAddress)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,103 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeServerAddress(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverAddress string
+		want          string
+	}{
+		{"bare host", "ghcr.io", "ghcr.io"},
+		{"https scheme", "https://ghcr.io", "ghcr.io"},
+		{"http scheme", "http://ghcr.io", "ghcr.io"},
+		{"trailing slash", "ghcr.io/", "ghcr.io"},
+		{"https with trailing slash", "https://ghcr.io/", "ghcr.io"},
+		{"docker hub legacy address", "https://index.docker.io/v1/", "index.docker.io/v1"},
+		{"uppercase host is lowercased", "https://GHCR.IO", "ghcr.io"},
+		{"port is preserved", "https://localhost:5000", "localhost:5000"},
+		{"port with path", "localhost:5000/myorg/repo", "localhost:5000/myorg/repo"},
+		{"path component", "ghcr.io/myorg/repo", "ghcr.io/myorg/repo"},
+		{"path is not lowercased", "ghcr.io/MyOrg/Repo", "ghcr.io/MyOrg/Repo"},
+		{"IDN host", "https://例え.jp/myorg", "例え.jp/myorg"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeServerAddress(tt.serverAddress); got != tt.want {
+				t.Errorf("NormalizeServerAddress(%q) = %q, want %q", tt.serverAddress, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixCandidates(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverAddress string
+		want          []string
+	}{
+		{
+			name:          "bare host",
+			serverAddress: "ghcr.io",
+			want:          []string{"ghcr.io"},
+		},
+		{
+			name:          "host and path",
+			serverAddress: "ghcr.io/myorg/repo",
+			want:          []string{"ghcr.io/myorg/repo", "ghcr.io/myorg", "ghcr.io"},
+		},
+		{
+			name:          "host with port and path",
+			serverAddress: "localhost:5000/myorg/repo",
+			want:          []string{"localhost:5000/myorg/repo", "localhost:5000/myorg", "localhost:5000"},
+		},
+		{
+			name:          "IDN host and path",
+			serverAddress: "例え.jp/myorg/repo",
+			want:          []string{"例え.jp/myorg/repo", "例え.jp/myorg", "例え.jp"},
+		},
+		{
+			name:          "empty",
+			serverAddress: "",
+			want:          []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrefixCandidates(tt.serverAddress); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PrefixCandidates(%q) = %v, want %v", tt.serverAddress, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeServerAddressThenPrefixCandidates(t *testing.T) {
+	// GetCredentialHelper and namespacedFileStore.Get both feed
+	// NormalizeServerAddress's output into PrefixCandidates; make sure a
+	// scheme-qualified, ported address with a path still yields the
+	// expected longest-to-shortest candidates.
+	got := PrefixCandidates(NormalizeServerAddress("https://Localhost:5000/MyOrg/Repo/"))
+	want := []string{"localhost:5000/MyOrg/Repo", "localhost:5000/MyOrg", "localhost:5000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,453 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oras-project/oras-credentials-go/internal/ioutil"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	// configFieldAuths is the "auths" field in the config file.
+	// Reference: https://github.com/docker/cli/blob/v24.0.0-beta.2/cli/config/configfile/file.go#L19
+	configFieldAuths = "auths"
+	// configFieldCredentialsStore is the "credsStore" field in the config file.
+	configFieldCredentialsStore = "credsStore"
+	// configFieldCredentialHelpers is the "credHelpers" field in the config file.
+	configFieldCredentialHelpers = "credHelpers"
+)
+
+// ErrInvalidConfigFormat is returned when the config format is invalid.
+var ErrInvalidConfigFormat = errors.New("invalid config format")
+
+// AuthConfig contains authorization information for connecting to a Registry.
+// References:
+//   - https://github.com/docker/cli/blob/v24.0.0-beta.2/cli/config/configfile/file.go#L17-L45
+//   - https://github.com/docker/cli/blob/v24.0.0-beta.2/cli/config/types/authconfig.go#L3-L22
+type AuthConfig struct {
+	// Auth is a base64-encoded string of "{username}:{password}".
+	Auth string `json:"auth,omitempty"`
+	// IdentityToken is used to authenticate the user and get an access token
+	// for the registry.
+	IdentityToken string `json:"identitytoken,omitempty"`
+	// RegistryToken is a bearer token to be sent to a registry.
+	RegistryToken string `json:"registrytoken,omitempty"`
+
+	Username string `json:"username,omitempty"` // legacy field for compatibility
+	Password string `json:"password,omitempty"` // legacy field for compatibility
+}
+
+// NewAuthConfig creates an authConfig based on cred.
+func NewAuthConfig(cred auth.Credential) AuthConfig {
+	return AuthConfig{
+		Auth:          encodeAuth(cred.Username, cred.Password),
+		IdentityToken: cred.RefreshToken,
+		RegistryToken: cred.AccessToken,
+	}
+}
+
+// Credential returns an auth.Credential based on ac.
+func (ac AuthConfig) Credential() (auth.Credential, error) {
+	cred := auth.Credential{
+		Username:     ac.Username,
+		Password:     ac.Password,
+		RefreshToken: ac.IdentityToken,
+		AccessToken:  ac.RegistryToken,
+	}
+	if ac.Auth != "" {
+		var err error
+		// override username and password
+		cred.Username, cred.Password, err = decodeAuth(ac.Auth)
+		if err != nil {
+			return auth.EmptyCredential, fmt.Errorf("failed to decode auth field: %w: %v", ErrInvalidConfigFormat, err)
+		}
+	}
+	return cred, nil
+}
+
+// Config represents a docker configuration file.
+// References:
+//   - https://docs.docker.com/engine/reference/commandline/cli/#docker-cli-configuration-file-configjson-properties
+//   - https://github.com/docker/cli/blob/v24.0.0-beta.2/cli/config/configfile/file.go#L17-L44
+type Config struct {
+	// path is the path to the config file.
+	path string
+	// rwLock is a read-write-lock for the file store.
+	rwLock sync.RWMutex
+	// content is the content of the config file.
+	// Reference: https://github.com/docker/cli/blob/v24.0.0-beta.2/cli/config/configfile/file.go#L17-L44
+	content map[string]json.RawMessage
+	// authsCache is a cache of the auths field of the config.
+	// Reference: https://github.com/docker/cli/blob/v24.0.0-beta.2/cli/config/configfile/file.go#L19
+	authsCache map[string]json.RawMessage
+	// credentialsStore is the credsStore field of the config.
+	// Reference: https://github.com/docker/cli/blob/v24.0.0-beta.2/cli/config/configfile/file.go#L28
+	credentialsStore string
+	// credentialHelpers is the credHelpers field of the config.
+	// Reference: https://github.com/docker/cli/blob/v24.0.0-beta.2/cli/config/configfile/file.go#L29
+	credentialHelpers map[string]string
+	// lockTimeout is the duration to wait for the advisory lock on the
+	// config file before giving up. Zero means DefaultFileLockTimeout.
+	lockTimeout time.Duration
+}
+
+// Load loads Config from the given config path.
+func Load(configPath string) (*Config, error) {
+	cfg := &Config{path: configPath}
+	if err := cfg.reload(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// reload re-reads the config file from disk, replacing cfg's in-memory
+// content and caches. Callers must hold cfg.rwLock and, for the
+// read-modify-write sequence to be safe across processes, the config file
+// lock.
+func (cfg *Config) reload() error {
+	configFile, err := os.Open(cfg.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// init content and caches if the content file does not exist
+			cfg.content = make(map[string]json.RawMessage)
+			cfg.authsCache = make(map[string]json.RawMessage)
+			cfg.credentialsStore = ""
+			cfg.credentialHelpers = nil
+			return nil
+		}
+		return fmt.Errorf("failed to open config file at %s: %w", cfg.path, err)
+	}
+	defer configFile.Close()
+
+	// decode config content if the config file exists
+	content := make(map[string]json.RawMessage)
+	if err := json.NewDecoder(configFile).Decode(&content); err != nil {
+		return fmt.Errorf("failed to decode config file at %s: %w: %v", cfg.path, ErrInvalidConfigFormat, err)
+	}
+	cfg.content = content
+
+	cfg.credentialsStore = ""
+	if credsStoreBytes, ok := cfg.content[configFieldCredentialsStore]; ok {
+		if err := json.Unmarshal(credsStoreBytes, &cfg.credentialsStore); err != nil {
+			return fmt.Errorf("failed to unmarshal creds store field: %w: %v", ErrInvalidConfigFormat, err)
+		}
+	}
+
+	cfg.credentialHelpers = nil
+	if credHelpersBytes, ok := cfg.content[configFieldCredentialHelpers]; ok {
+		if err := json.Unmarshal(credHelpersBytes, &cfg.credentialHelpers); err != nil {
+			return fmt.Errorf("failed to unmarshal cred helpers field: %w: %v", ErrInvalidConfigFormat, err)
+		}
+	}
+
+	cfg.authsCache = nil
+	if authsBytes, ok := cfg.content[configFieldAuths]; ok {
+		if err := json.Unmarshal(authsBytes, &cfg.authsCache); err != nil {
+			return fmt.Errorf("failed to unmarshal auths field: %w: %v", ErrInvalidConfigFormat, err)
+		}
+	}
+	if cfg.authsCache == nil {
+		cfg.authsCache = make(map[string]json.RawMessage)
+	}
+
+	return nil
+}
+
+// SetFileLockTimeout sets the duration to wait for the advisory lock on the
+// config file before giving up with ErrConfigLocked. A zero value restores
+// DefaultFileLockTimeout.
+func (cfg *Config) SetFileLockTimeout(timeout time.Duration) {
+	cfg.rwLock.Lock()
+	defer cfg.rwLock.Unlock()
+
+	cfg.lockTimeout = timeout
+}
+
+// fileLockTimeout returns the configured file lock timeout, or
+// DefaultFileLockTimeout if none was set.
+func (cfg *Config) fileLockTimeout() time.Duration {
+	if cfg.lockTimeout > 0 {
+		return cfg.lockTimeout
+	}
+	return DefaultFileLockTimeout
+}
+
+// withFileLock acquires the advisory lock on the config file, reloads the
+// config from disk so that mutate observes any changes made by other
+// processes since cfg was last loaded, then invokes mutate to apply and
+// persist the intended change. The lock is held across the entire
+// read-modify-write and is always released, including on error.
+func (cfg *Config) withFileLock(mutate func() error) (returnErr error) {
+	configDir := filepath.Dir(cfg.path)
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return fmt.Errorf("failed to make directory %s: %w", configDir, err)
+	}
+
+	lock, err := lockConfigFile(cfg.path, cfg.fileLockTimeout())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil && returnErr == nil {
+			returnErr = fmt.Errorf("failed to release config lock file: %w", err)
+		}
+	}()
+
+	if err := cfg.reload(); err != nil {
+		return err
+	}
+	return mutate()
+}
+
+// GetCredential returns an auth.Credential for serverAddress, matching
+// serverAddress against the stored "auths" keys with NormalizeServerAddress
+// so that e.g. "https://ghcr.io/" and "ghcr.io" resolve to the same entry
+// regardless of which form the credential was saved under.
+func (cfg *Config) GetCredential(serverAddress string) (auth.Credential, error) {
+	cfg.rwLock.RLock()
+	defer cfg.rwLock.RUnlock()
+
+	key, ok := cfg.matchAuthKey(serverAddress)
+	if !ok {
+		return auth.EmptyCredential, nil
+	}
+	var authCfg AuthConfig
+	if err := json.Unmarshal(cfg.authsCache[key], &authCfg); err != nil {
+		return auth.EmptyCredential, fmt.Errorf("failed to unmarshal auth field: %w: %v", ErrInvalidConfigFormat, err)
+	}
+	return authCfg.Credential()
+}
+
+// matchAuthKey returns the authsCache key that corresponds to serverAddress,
+// if any. An exact match is tried first; otherwise the keys are compared
+// with NormalizeServerAddress so that a credential saved under a
+// scheme-qualified or differently-cased address can still be found. Callers
+// must hold cfg.rwLock.
+func (cfg *Config) matchAuthKey(serverAddress string) (string, bool) {
+	if _, ok := cfg.authsCache[serverAddress]; ok {
+		return serverAddress, true
+	}
+	normalized := NormalizeServerAddress(serverAddress)
+	for key := range cfg.authsCache {
+		if NormalizeServerAddress(key) == normalized {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// PutCredential puts cred for serverAddress. If an entry already exists for
+// serverAddress under an equivalent address (see matchAuthKey), it is
+// overwritten in place; otherwise a new entry is added using serverAddress
+// as given, preserving the on-disk key format expected by the Docker CLI.
+func (cfg *Config) PutCredential(serverAddress string, cred auth.Credential) error {
+	cfg.rwLock.Lock()
+	defer cfg.rwLock.Unlock()
+
+	authCfg := NewAuthConfig(cred)
+	authCfgBytes, err := json.Marshal(authCfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal auth field: %w", err)
+	}
+	return cfg.withFileLock(func() error {
+		key := serverAddress
+		if existing, ok := cfg.matchAuthKey(serverAddress); ok {
+			key = existing
+		}
+		cfg.authsCache[key] = authCfgBytes
+		return cfg.saveFile()
+	})
+}
+
+// DeleteCredential deletes the corresponding credential for serverAddress.
+func (cfg *Config) DeleteCredential(serverAddress string) error {
+	cfg.rwLock.Lock()
+	defer cfg.rwLock.Unlock()
+
+	return cfg.withFileLock(func() error {
+		key, ok := cfg.matchAuthKey(serverAddress)
+		if !ok {
+			// no ops
+			return nil
+		}
+		delete(cfg.authsCache, key)
+		return cfg.saveFile()
+	})
+}
+
+// GetCredentialHelper returns the credential helper configured for
+// serverAddress, trying the longest matching namespace prefix of
+// serverAddress first (e.g. for "ghcr.io/myorg/repo": "ghcr.io/myorg/repo",
+// then "ghcr.io/myorg", then "ghcr.io"). Both serverAddress and the
+// configured credHelpers keys are normalized with NormalizeServerAddress
+// before comparison.
+func (cfg *Config) GetCredentialHelper(serverAddress string) string {
+	cfg.rwLock.RLock()
+	defer cfg.rwLock.RUnlock()
+
+	for _, candidate := range PrefixCandidates(NormalizeServerAddress(serverAddress)) {
+		for configuredAddress, helper := range cfg.credentialHelpers {
+			if NormalizeServerAddress(configuredAddress) == candidate {
+				return helper
+			}
+		}
+	}
+	return ""
+}
+
+// CredentialHelpers returns the configured "credHelpers" suffixes.
+func (cfg *Config) CredentialHelpers() map[string]string {
+	cfg.rwLock.RLock()
+	defer cfg.rwLock.RUnlock()
+
+	helpers := make(map[string]string, len(cfg.credentialHelpers))
+	for serverAddress, helper := range cfg.credentialHelpers {
+		helpers[serverAddress] = helper
+	}
+	return helpers
+}
+
+// CredentialsStore returns the configured credentials store.
+func (cfg *Config) CredentialsStore() string {
+	cfg.rwLock.RLock()
+	defer cfg.rwLock.RUnlock()
+
+	return cfg.credentialsStore
+}
+
+// SetCredentialsStore puts the configured credentials store.
+func (cfg *Config) SetCredentialsStore(credsStore string) error {
+	cfg.rwLock.Lock()
+	defer cfg.rwLock.Unlock()
+
+	return cfg.withFileLock(func() error {
+		cfg.credentialsStore = credsStore
+		return cfg.saveFile()
+	})
+}
+
+// IsAuthConfigured returns whether there is authentication configured in this
+// config file or not.
+func (cfg *Config) IsAuthConfigured() bool {
+	return cfg.credentialsStore != "" ||
+		len(cfg.credentialHelpers) > 0 ||
+		len(cfg.authsCache) > 0
+}
+
+// List lists the server addresses that have plain-text credentials stored
+// in the "auths" field, mapped to their associated usernames.
+func (cfg *Config) List() (map[string]string, error) {
+	cfg.rwLock.RLock()
+	defer cfg.rwLock.RUnlock()
+
+	serverAddressToUsername := make(map[string]string, len(cfg.authsCache))
+	for serverAddress, authCfgBytes := range cfg.authsCache {
+		var authCfg AuthConfig
+		if err := json.Unmarshal(authCfgBytes, &authCfg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal auth field for %s: %w: %v", serverAddress, ErrInvalidConfigFormat, err)
+		}
+		cred, err := authCfg.Credential()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve credential for %s: %w", serverAddress, err)
+		}
+		serverAddressToUsername[serverAddress] = cred.Username
+	}
+	return serverAddressToUsername, nil
+}
+
+// saveFile saves Config into the file.
+func (cfg *Config) saveFile() (returnErr error) {
+	// marshal content
+	// credentialHelpers is skipped as it's never set
+	if cfg.credentialsStore != "" {
+		credsStoreBytes, err := json.Marshal(cfg.credentialsStore)
+		if err != nil {
+			return fmt.Errorf("failed to marshal creds store: %w", err)
+		}
+		cfg.content[configFieldCredentialsStore] = credsStoreBytes
+	} else {
+		// omit empty
+		delete(cfg.content, configFieldCredentialsStore)
+	}
+	authsBytes, err := json.Marshal(cfg.authsCache)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+	cfg.content[configFieldAuths] = authsBytes
+	jsonBytes, err := json.MarshalIndent(cfg.content, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	// write the content to a ingest file for atomicity
+	configDir := filepath.Dir(cfg.path)
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return fmt.Errorf("failed to make directory %s: %w", configDir, err)
+	}
+	ingest, err := ioutil.Ingest(configDir, bytes.NewReader(jsonBytes))
+	if err != nil {
+		return fmt.Errorf("failed to save config file: %w", err)
+	}
+	defer func() {
+		if returnErr != nil {
+			// clean up the ingest file in case of error
+			os.Remove(ingest)
+		}
+	}()
+
+	// overwrite the config file
+	if err := os.Rename(ingest, cfg.path); err != nil {
+		return fmt.Errorf("failed to save config file: %w", err)
+	}
+	return nil
+}
+
+// encodeAuth base64-encodes username and password into base64(username:password).
+func encodeAuth(username, password string) string {
+	if username == "" && password == "" {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// decodeAuth decodes a base64 encoded string and returns username and password.
+func decodeAuth(authStr string) (username string, password string, err error) {
+	if authStr == "" {
+		return "", "", nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(authStr)
+	if err != nil {
+		return "", "", err
+	}
+	decodedStr := string(decoded)
+	username, password, ok := strings.Cut(decodedStr, ":")
+	if !ok {
+		return "", "", fmt.Errorf("auth '%s' does not conform the base64(username:password) format", decodedStr)
+	}
+	return username, password, nil
+}
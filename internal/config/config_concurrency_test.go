@@ -0,0 +1,243 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// helperProcessEnv, when set to "1" in a child process's environment, tells
+// TestMain to run runPutCredentialHelperProcess instead of the test suite.
+const helperProcessEnv = "ORAS_CREDENTIALS_GO_FILELOCK_TEST_HELPER_PROCESS"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperProcessEnv) == "1" {
+		runPutCredentialHelperProcess()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runPutCredentialHelperProcess loads the config at
+// $ORAS_CREDENTIALS_GO_TEST_CONFIG_PATH and puts a credential for
+// $ORAS_CREDENTIALS_GO_TEST_SERVER_ADDRESS, exiting non-zero on error. It
+// lets TestConfigPutCredentialConcurrentProcesses exercise the file lock
+// across real OS processes, not just goroutines sharing one Config's
+// in-memory rwLock.
+func runPutCredentialHelperProcess() {
+	configPath := os.Getenv("ORAS_CREDENTIALS_GO_TEST_CONFIG_PATH")
+	serverAddress := os.Getenv("ORAS_CREDENTIALS_GO_TEST_SERVER_ADDRESS")
+	cfg, err := Load(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	cred := auth.Credential{Username: "user-" + serverAddress}
+	if err := cfg.PutCredential(serverAddress, cred); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// TestConfigPutCredentialCreatesConfigDir is a regression test for
+// withFileLock acquiring the advisory lock before the config directory
+// exists: on a machine with no pre-existing config directory (e.g. a fresh
+// $HOME/.docker), the very first PutCredential must still succeed.
+func TestConfigPutCredentialCreatesConfigDir(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "does", "not", "exist", "yet", "config.json")
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := cfg.PutCredential("ghcr.io", auth.Credential{Username: "user"}); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+	if _, err := os.Stat(configPath); err != nil {
+		t.Fatalf("config file was not created: %v", err)
+	}
+}
+
+// TestConfigPutCredentialConcurrentGoroutines hammers PutCredential from
+// many goroutines, each loading its own *Config (so correctness depends on
+// the file lock's read-reload-mutate-save cycle, not Config's in-memory
+// rwLock), and asserts every credential survives.
+func TestConfigPutCredentialConcurrentGoroutines(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cfg, err := Load(configPath)
+			if err != nil {
+				errs <- err
+				return
+			}
+			serverAddress := fmt.Sprintf("registry%d.example.com", i)
+			cred := auth.Credential{Username: fmt.Sprintf("user%d", i)}
+			if err := cfg.PutCredential(serverAddress, cred); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("PutCredential failed: %v", err)
+	}
+
+	final, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, err := final.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != goroutines {
+		t.Fatalf("List() returned %d credentials, want %d (lost update under concurrent Put)", len(got), goroutines)
+	}
+	for i := 0; i < goroutines; i++ {
+		serverAddress := fmt.Sprintf("registry%d.example.com", i)
+		want := fmt.Sprintf("user%d", i)
+		if got[serverAddress] != want {
+			t.Errorf("List()[%q] = %q, want %q", serverAddress, got[serverAddress], want)
+		}
+	}
+}
+
+// TestConfigPutCredentialConcurrentProcesses is the same test as
+// TestConfigPutCredentialConcurrentGoroutines, but each PutCredential runs
+// in its own OS process, so the test only passes if the advisory lock
+// actually serializes writers across processes rather than just goroutines.
+func TestConfigPutCredentialConcurrentProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+	configPath := filepath.Join(t.TempDir(), "config.json")
+
+	const processes = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, processes)
+	for i := 0; i < processes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			serverAddress := fmt.Sprintf("registry%d.example.com", i)
+			cmd := exec.Command(os.Args[0])
+			cmd.Env = append(os.Environ(),
+				helperProcessEnv+"=1",
+				"ORAS_CREDENTIALS_GO_TEST_CONFIG_PATH="+configPath,
+				"ORAS_CREDENTIALS_GO_TEST_SERVER_ADDRESS="+serverAddress,
+			)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				errs <- fmt.Errorf("helper process for %s failed: %w: %s", serverAddress, err, out)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	final, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, err := final.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != processes {
+		t.Fatalf("List() returned %d credentials, want %d (lost update under concurrent cross-process Put)", len(got), processes)
+	}
+}
+
+// TestConfigDeleteCredentialConcurrent puts a batch of credentials, then
+// concurrently deletes half of them, asserting the other half survive and
+// the deleted half are actually gone.
+func TestConfigDeleteCredentialConcurrent(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	const total = 40
+	for i := 0; i < total; i++ {
+		serverAddress := fmt.Sprintf("registry%d.example.com", i)
+		if err := cfg.PutCredential(serverAddress, auth.Credential{Username: fmt.Sprintf("user%d", i)}); err != nil {
+			t.Fatalf("PutCredential(%d) error = %v", i, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, total/2)
+	for i := 0; i < total; i += 2 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := Load(configPath)
+			if err != nil {
+				errs <- err
+				return
+			}
+			serverAddress := fmt.Sprintf("registry%d.example.com", i)
+			if err := c.DeleteCredential(serverAddress); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("DeleteCredential failed: %v", err)
+	}
+
+	final, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, err := final.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != total/2 {
+		t.Fatalf("List() returned %d credentials, want %d", len(got), total/2)
+	}
+	for i := 0; i < total; i++ {
+		serverAddress := fmt.Sprintf("registry%d.example.com", i)
+		_, ok := got[serverAddress]
+		if i%2 == 0 && ok {
+			t.Errorf("registry%d was deleted but is still present", i)
+		}
+		if i%2 == 1 && !ok {
+			t.Errorf("registry%d should have survived but is missing", i)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// TestConfigGetCredentialNormalizedAddress is a regression test for
+// PutCredential storing the raw, un-normalized serverAddress while reads
+// that go through PrefixCandidates(NormalizeServerAddress(...)) (as
+// namespacedFileStore.Get does) looked it up by its normalized form: a
+// credential saved under a scheme-qualified address must still be found by
+// its normalized, bare-host-and-path form.
+func TestConfigGetCredentialNormalizedAddress(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := auth.Credential{Username: "u", Password: "p"}
+	if err := cfg.PutCredential("https://registry.example.com/myorg", want); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+
+	for _, candidate := range PrefixCandidates(NormalizeServerAddress("registry.example.com/myorg/repo")) {
+		if candidate != "registry.example.com/myorg" {
+			continue
+		}
+		got, err := cfg.GetCredential(candidate)
+		if err != nil {
+			t.Fatalf("GetCredential(%q) error = %v", candidate, err)
+		}
+		if got != want {
+			t.Errorf("GetCredential(%q) = %+v, want %+v", candidate, got, want)
+		}
+		return
+	}
+	t.Fatal("registry.example.com/myorg not in prefix candidates")
+}
+
+// TestConfigPutCredentialOverwritesExistingKey ensures that re-putting a
+// credential under an address equivalent to (but not identical to) an
+// already-stored key updates the existing entry in place instead of adding
+// a second, shadowing entry.
+func TestConfigPutCredentialOverwritesExistingKey(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err := cfg.PutCredential("https://ghcr.io/", auth.Credential{Username: "first"}); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+	if err := cfg.PutCredential("ghcr.io", auth.Credential{Username: "second"}); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+
+	list, err := cfg.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("List() returned %d entries, want 1 (equivalent addresses should share one entry): %v", len(list), list)
+	}
+	got, err := cfg.GetCredential("ghcr.io")
+	if err != nil {
+		t.Fatalf("GetCredential() error = %v", err)
+	}
+	if got.Username != "second" {
+		t.Errorf("GetCredential().Username = %q, want %q", got.Username, "second")
+	}
+}
+
+// TestConfigDeleteCredentialNormalizedAddress ensures DeleteCredential can
+// remove a credential saved under a different, but equivalent, address
+// spelling.
+func TestConfigDeleteCredentialNormalizedAddress(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := cfg.PutCredential("https://ghcr.io/", auth.Credential{Username: "user"}); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+	if err := cfg.DeleteCredential("ghcr.io"); err != nil {
+		t.Fatalf("DeleteCredential() error = %v", err)
+	}
+	got, err := cfg.GetCredential("https://ghcr.io/")
+	if err != nil {
+		t.Fatalf("GetCredential() error = %v", err)
+	}
+	if got != auth.EmptyCredential {
+		t.Errorf("GetCredential() = %+v after delete, want EmptyCredential", got)
+	}
+}
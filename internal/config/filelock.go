@@ -0,0 +1,74 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultFileLockTimeout is the default duration to wait for the advisory
+// lock on the config file before giving up.
+const DefaultFileLockTimeout = 5 * time.Second
+
+// ErrConfigLocked is returned when the advisory lock on the config file
+// could not be acquired within the configured timeout.
+var ErrConfigLocked = errors.New("timed out waiting for the config file lock")
+
+// lockPollInterval is how often an unavailable lock is retried while
+// waiting for it to be released.
+const lockPollInterval = 50 * time.Millisecond
+
+// fileLock is an OS-level advisory lock held on a sibling "<path>.lock"
+// file, used to serialize config file reads and writes across processes.
+type fileLock struct {
+	file *os.File
+}
+
+// lockConfigFile acquires an exclusive advisory lock on a sibling lock file
+// of path, polling every lockPollInterval until it succeeds or timeout
+// elapses, in which case ErrConfigLocked is returned.
+func lockConfigFile(path string, timeout time.Duration) (*fileLock, error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryLockFile(f)
+		if err == nil {
+			return &fileLock{file: f}, nil
+		}
+		if !errors.Is(err, errFileLocked) {
+			f.Close()
+			return nil, fmt.Errorf("failed to acquire config lock file: %w", err)
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, ErrConfigLocked
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// Unlock releases the lock and closes the underlying lock file.
+func (l *fileLock) Unlock() error {
+	defer l.file.Close()
+	return unlockFile(l.file)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretservice implements a minimal, pure-Go client for the
+// freedesktop.org Secret Service D-Bus API, used to store credentials in
+// the user's native Linux keyring (e.g. GNOME Keyring, KWallet) without
+// cgo or a dependency on libsecret.
+//
+// Reference: https://specifications.freedesktop.org/secret-service-spec/latest/
+package secretservice
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusServiceName         = "org.freedesktop.secrets"
+	dbusPath                = dbus.ObjectPath("/org/freedesktop/secrets")
+	dbusInterfaceService    = "org.freedesktop.Secret.Service"
+	dbusInterfaceCollection = "org.freedesktop.Secret.Collection"
+	dbusInterfaceItem       = "org.freedesktop.Secret.Item"
+	defaultCollectionPath   = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+)
+
+// ErrNoSessionBus is returned when no D-Bus session bus, or no Secret
+// Service provider on it, could be reached.
+var ErrNoSessionBus = errors.New("no D-Bus session bus available")
+
+// secretStruct mirrors the Secret Service "Secret" struct:
+// (session, parameters, value, content_type).
+type secretStruct struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// Client is a connection to the org.freedesktop.secrets D-Bus service,
+// with a plain-text session already negotiated.
+type Client struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+// New connects to the D-Bus session bus and opens a plain-text Secret
+// Service session on the default collection. It returns ErrNoSessionBus if
+// no session bus or Secret Service provider is reachable.
+func New() (*Client, error) {
+	conn, err := dbus.SessionBusPrivate()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoSessionBus, err)
+	}
+	if err := conn.Auth(nil); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrNoSessionBus, err)
+	}
+	if err := conn.Hello(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrNoSessionBus, err)
+	}
+
+	service := conn.Object(dbusServiceName, dbusPath)
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	call := service.Call(dbusInterfaceService+".OpenSession", 0, "plain", dbus.MakeVariant(""))
+	if err := call.Store(&output, &session); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrNoSessionBus, err)
+	}
+
+	return &Client{conn: conn, session: session}, nil
+}
+
+// Close releases the underlying D-Bus connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) collection() dbus.BusObject {
+	return c.conn.Object(dbusServiceName, defaultCollectionPath)
+}
+
+// Set creates or replaces the item identified by attributes in the default
+// collection, labelling it label and storing value as its secret.
+func (c *Client) Set(label string, attributes map[string]string, value []byte) error {
+	props := map[string]dbus.Variant{
+		"org.freedesktop.Secret.Item.Label":      dbus.MakeVariant(label),
+		"org.freedesktop.Secret.Item.Attributes": dbus.MakeVariant(attributes),
+	}
+	s := secretStruct{
+		Session:     c.session,
+		Parameters:  []byte{},
+		Value:       value,
+		ContentType: "text/plain; charset=utf8",
+	}
+	var item, prompt dbus.ObjectPath
+	call := c.collection().Call(dbusInterfaceCollection+".CreateItem", 0, props, s, true)
+	if err := call.Store(&item, &prompt); err != nil {
+		return fmt.Errorf("failed to create secret item: %w", err)
+	}
+	return nil
+}
+
+// find returns the object paths of every item matching attributes.
+func (c *Client) find(attributes map[string]string) ([]dbus.ObjectPath, error) {
+	service := c.conn.Object(dbusServiceName, dbusPath)
+	var unlocked, locked []dbus.ObjectPath
+	call := service.Call(dbusInterfaceService+".SearchItems", 0, attributes)
+	if err := call.Store(&unlocked, &locked); err != nil {
+		return nil, fmt.Errorf("failed to search secret items: %w", err)
+	}
+	return append(unlocked, locked...), nil
+}
+
+// Get returns the secret of the item matching attributes, or a nil slice
+// if no such item exists.
+func (c *Client) Get(attributes map[string]string) ([]byte, error) {
+	items, err := c.find(attributes)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	item := c.conn.Object(dbusServiceName, items[0])
+	var s secretStruct
+	if err := item.Call(dbusInterfaceItem+".GetSecret", 0, c.session).Store(&s); err != nil {
+		return nil, fmt.Errorf("failed to read secret item: %w", err)
+	}
+	return s.Value, nil
+}
+
+// Delete removes every item matching attributes.
+func (c *Client) Delete(attributes map[string]string) error {
+	items, err := c.find(attributes)
+	if err != nil {
+		return err
+	}
+	for _, path := range items {
+		item := c.conn.Object(dbusServiceName, path)
+		var prompt dbus.ObjectPath
+		if err := item.Call(dbusInterfaceItem+".Delete", 0).Store(&prompt); err != nil {
+			return fmt.Errorf("failed to delete secret item: %w", err)
+		}
+	}
+	return nil
+}
+
+// List returns the attributes of every item in the default collection.
+func (c *Client) List() ([]map[string]string, error) {
+	itemsProp, err := c.collection().GetProperty(dbusInterfaceCollection + ".Items")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secret items: %w", err)
+	}
+	paths, ok := itemsProp.Value().([]dbus.ObjectPath)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for the collection Items property")
+	}
+
+	result := make([]map[string]string, 0, len(paths))
+	for _, path := range paths {
+		item := c.conn.Object(dbusServiceName, path)
+		attrsProp, err := item.GetProperty(dbusInterfaceItem + ".Attributes")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret item attributes: %w", err)
+		}
+		attrs, ok := attrsProp.Value().(map[string]string)
+		if !ok {
+			continue
+		}
+		result = append(result, attrs)
+	}
+	return result, nil
+}
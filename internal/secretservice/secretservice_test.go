@@ -0,0 +1,301 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretservice
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// TestSecretStructWireSignature is a regression test for secretStruct's
+// field order and types: the Secret Service spec defines the Secret struct
+// as (session, parameters, value, content_type), wire signature "(oayays)".
+// A provider rejects CreateItem/GetSecret calls if this shape drifts.
+func TestSecretStructWireSignature(t *testing.T) {
+	got := dbus.SignatureOf(secretStruct{}).String()
+	const want = "(oayays)"
+	if got != want {
+		t.Errorf("SignatureOf(secretStruct{}) = %q, want %q", got, want)
+	}
+}
+
+// fakeDBusHandler replies to one D-Bus method call identified by
+// "interface.member", given the call's decoded body.
+type fakeDBusHandler func(args []interface{}) (reply []interface{}, dbusErr *dbus.Error)
+
+// fakeDBusServer is a minimal D-Bus peer speaking the wire protocol directly
+// over a net.Pipe, used to verify the exact method calls and argument shapes
+// Client sends, without a real D-Bus session bus.
+type fakeDBusServer struct {
+	t        *testing.T
+	conn     net.Conn
+	handlers map[string]fakeDBusHandler
+}
+
+func newFakeDBusServer(t *testing.T) (*Client, *fakeDBusServer) {
+	t.Helper()
+	clientSide, serverSide := net.Pipe()
+	t.Cleanup(func() { clientSide.Close(); serverSide.Close() })
+
+	conn, err := dbus.NewConn(clientSide)
+	if err != nil {
+		t.Fatalf("dbus.NewConn() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	server := &fakeDBusServer{t: t, conn: serverSide, handlers: make(map[string]fakeDBusHandler)}
+	go server.handshakeAndServe()
+
+	// The EXTERNAL mechanism always succeeds against our fake server below,
+	// regardless of the uid it carries, so Auth(nil) (the same call New
+	// makes) completes the real SASL handshake dbus.Conn requires before it
+	// will process any method call.
+	if err := conn.Auth(nil); err != nil {
+		t.Fatalf("Conn.Auth() error = %v", err)
+	}
+
+	return &Client{conn: conn, session: dbus.ObjectPath("/org/freedesktop/secrets/session/test")}, server
+}
+
+// on registers the reply for a given "interface.member" method call.
+func (s *fakeDBusServer) on(ifaceMember string, h fakeDBusHandler) {
+	s.handlers[ifaceMember] = h
+}
+
+// handshakeAndServe performs the minimal SASL handshake dbus.Conn.Auth
+// requires (NUL byte, "AUTH", "AUTH EXTERNAL <uid>", "BEGIN") before
+// switching to the binary D-Bus message protocol and serving method calls.
+func (s *fakeDBusServer) handshakeAndServe() {
+	br := bufio.NewReader(s.conn)
+	if _, err := br.ReadByte(); err != nil { // the leading NUL byte
+		return
+	}
+	if _, err := br.ReadString('\n'); err != nil { // "AUTH\r\n"
+		return
+	}
+	if _, err := s.conn.Write([]byte("REJECTED EXTERNAL\r\n")); err != nil {
+		return
+	}
+	if _, err := br.ReadString('\n'); err != nil { // "AUTH EXTERNAL <hex-uid>\r\n"
+		return
+	}
+	if _, err := s.conn.Write([]byte("OK 0123456789abcdef0123456789abcdef\r\n")); err != nil {
+		return
+	}
+	if _, err := br.ReadString('\n'); err != nil { // "BEGIN\r\n"
+		return
+	}
+
+	for {
+		msg, err := dbus.DecodeMessage(br)
+		if err != nil {
+			return
+		}
+		if msg.Type != dbus.TypeMethodCall {
+			continue
+		}
+		iface, _ := msg.Headers[dbus.FieldInterface].Value().(string)
+		member, _ := msg.Headers[dbus.FieldMember].Value().(string)
+		key := iface + "." + member
+
+		var reply *dbus.Message
+		h, ok := s.handlers[key]
+		if !ok {
+			reply = newFakeDBusErrorMessage(msg.Serial(), "org.freedesktop.DBus.Error.UnknownMethod",
+				fmt.Sprintf("fakeDBusServer: no handler registered for %s", key))
+		} else if body, dbusErr := h(msg.Body); dbusErr != nil {
+			reply = newFakeDBusErrorMessage(msg.Serial(), dbusErr.Name, dbusErr.Body...)
+		} else {
+			reply = newFakeDBusReplyMessage(msg.Serial(), body...)
+		}
+
+		if err := reply.EncodeTo(s.conn, binary.LittleEndian); err != nil {
+			return
+		}
+	}
+}
+
+func newFakeDBusReplyMessage(replySerial uint32, body ...interface{}) *dbus.Message {
+	msg := new(dbus.Message)
+	msg.Type = dbus.TypeMethodReply
+	msg.Headers = map[dbus.HeaderField]dbus.Variant{
+		dbus.FieldReplySerial: dbus.MakeVariant(replySerial),
+	}
+	msg.Body = body
+	if len(body) > 0 {
+		msg.Headers[dbus.FieldSignature] = dbus.MakeVariant(dbus.SignatureOf(body...))
+	}
+	return msg
+}
+
+func newFakeDBusErrorMessage(replySerial uint32, name string, body ...interface{}) *dbus.Message {
+	msg := newFakeDBusReplyMessage(replySerial, body...)
+	msg.Type = dbus.TypeError
+	msg.Headers[dbus.FieldErrorName] = dbus.MakeVariant(name)
+	return msg
+}
+
+// TestClientSetCreatesItemWithSecretStruct asserts that Set calls
+// Collection.CreateItem with the item's label/attributes properties and a
+// secretStruct carrying the session, value and content type, exactly as the
+// Secret Service API expects.
+func TestClientSetCreatesItemWithSecretStruct(t *testing.T) {
+	client, server := newFakeDBusServer(t)
+
+	var gotProps map[string]dbus.Variant
+	var gotSecret secretStruct
+	server.on(dbusInterfaceCollection+".CreateItem", func(args []interface{}) ([]interface{}, *dbus.Error) {
+		if len(args) != 3 {
+			t.Fatalf("CreateItem called with %d args, want 3", len(args))
+		}
+		var ok bool
+		gotProps, ok = args[0].(map[string]dbus.Variant)
+		if !ok {
+			t.Fatalf("CreateItem properties arg has type %T, want map[string]dbus.Variant", args[0])
+		}
+		if err := dbus.Store([]interface{}{args[1]}, &gotSecret); err != nil {
+			t.Fatalf("failed to decode secretStruct arg: %v", err)
+		}
+		return []interface{}{dbus.ObjectPath("/item/1"), dbus.ObjectPath("/")}, nil
+	})
+
+	attrs := map[string]string{"server": "ghcr.io"}
+	if err := client.Set("ghcr.io", attrs, []byte("secret-value")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if label, _ := gotProps[dbusInterfaceItem+".Label"].Value().(string); label != "ghcr.io" {
+		t.Errorf("CreateItem properties label = %q, want %q", label, "ghcr.io")
+	}
+	if gotSecret.Session != client.session {
+		t.Errorf("secretStruct.Session = %q, want %q", gotSecret.Session, client.session)
+	}
+	if string(gotSecret.Value) != "secret-value" {
+		t.Errorf("secretStruct.Value = %q, want %q", gotSecret.Value, "secret-value")
+	}
+	if gotSecret.ContentType == "" {
+		t.Error("secretStruct.ContentType is empty")
+	}
+}
+
+// TestClientGetSearchesThenReadsSecret asserts that Get first calls
+// Service.SearchItems with the given attributes, then reads the secret of
+// the first matching item via Item.GetSecret, passing the session.
+func TestClientGetSearchesThenReadsSecret(t *testing.T) {
+	client, server := newFakeDBusServer(t)
+
+	server.on(dbusInterfaceService+".SearchItems", func(args []interface{}) ([]interface{}, *dbus.Error) {
+		return []interface{}{
+			[]dbus.ObjectPath{"/item/1"}, // unlocked
+			[]dbus.ObjectPath{},          // locked
+		}, nil
+	})
+	var gotSession dbus.ObjectPath
+	server.on(dbusInterfaceItem+".GetSecret", func(args []interface{}) ([]interface{}, *dbus.Error) {
+		if len(args) != 1 {
+			t.Fatalf("GetSecret called with %d args, want 1", len(args))
+		}
+		gotSession, _ = args[0].(dbus.ObjectPath)
+		return []interface{}{secretStruct{
+			Session:     client.session,
+			Parameters:  []byte{},
+			Value:       []byte("stored-secret"),
+			ContentType: "text/plain; charset=utf8",
+		}}, nil
+	})
+
+	got, err := client.Get(map[string]string{"server": "ghcr.io"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if string(got) != "stored-secret" {
+		t.Errorf("Get() = %q, want %q", got, "stored-secret")
+	}
+	if gotSession != client.session {
+		t.Errorf("GetSecret called with session %q, want %q", gotSession, client.session)
+	}
+}
+
+// TestClientGetNoMatchingItemsReturnsNil asserts that Get returns a nil
+// slice, not an error, when SearchItems finds no matching item.
+func TestClientGetNoMatchingItemsReturnsNil(t *testing.T) {
+	client, server := newFakeDBusServer(t)
+	server.on(dbusInterfaceService+".SearchItems", func(args []interface{}) ([]interface{}, *dbus.Error) {
+		return []interface{}{[]dbus.ObjectPath{}, []dbus.ObjectPath{}}, nil
+	})
+
+	got, err := client.Get(map[string]string{"server": "unknown.example.com"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Get() = %v, want nil", got)
+	}
+}
+
+// TestClientListReadsAttributesOfEveryCollectionItem asserts that List
+// reads the collection's Items property, then the Attributes property of
+// each item, via the org.freedesktop.DBus.Properties.Get protocol.
+func TestClientListReadsAttributesOfEveryCollectionItem(t *testing.T) {
+	client, server := newFakeDBusServer(t)
+
+	server.on("org.freedesktop.DBus.Properties.Get", func(args []interface{}) ([]interface{}, *dbus.Error) {
+		if len(args) != 2 {
+			t.Fatalf("Properties.Get called with %d args, want 2", len(args))
+		}
+		iface, _ := args[0].(string)
+		prop, _ := args[1].(string)
+		switch {
+		case iface == dbusInterfaceCollection && prop == "Items":
+			return []interface{}{dbus.MakeVariant([]dbus.ObjectPath{"/item/1", "/item/2"})}, nil
+		case iface == dbusInterfaceItem && prop == "Attributes":
+			return []interface{}{dbus.MakeVariant(map[string]string{"server": "ghcr.io"})}, nil
+		default:
+			return nil, dbus.NewError("org.freedesktop.DBus.Error.UnknownProperty", []interface{}{"unexpected property " + iface + "." + prop})
+		}
+	})
+
+	got, err := client.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("List() returned %d entries, want 2 (one per collection item)", len(got))
+	}
+	for _, attrs := range got {
+		if attrs["server"] != "ghcr.io" {
+			t.Errorf("List() entry = %v, want server=ghcr.io", attrs)
+		}
+	}
+}
+
+// TestNewReturnsErrNoSessionBusWhenUnreachable asserts that New returns
+// ErrNoSessionBus when the D-Bus session bus address points nowhere, rather
+// than a raw dbus package error.
+func TestNewReturnsErrNoSessionBusWhenUnreachable(t *testing.T) {
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/nonexistent/oras-credentials-go-test.sock")
+
+	_, err := New()
+	if !errors.Is(err, ErrNoSessionBus) {
+		t.Errorf("New() error = %v, want it to wrap ErrNoSessionBus", err)
+	}
+}
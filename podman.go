@@ -0,0 +1,185 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/oras-project/oras-credentials-go/internal/config"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	xdgRuntimeDirEnv       = "XDG_RUNTIME_DIR"
+	containersConfigDir    = "containers"
+	containersAuthFileName = "auth.json"
+)
+
+// podmanStore resolves credentials from the Podman/Skopeo/Buildah auth.json
+// file. Unlike the Docker config file, auth.json entries may be namespaced
+// (e.g. "registry.example.com/myorg"), so Get() and getHelperSuffix() try
+// the longest matching path prefix of the server address before falling
+// back to the bare host.
+type podmanStore struct {
+	config  *config.Config
+	options StoreOptions
+}
+
+// NewStoreFromPodman returns a Store based on the Podman/Skopeo/Buildah
+// auth.json file. The following locations are searched in order, and the
+// first one that exists is used:
+//   - $XDG_RUNTIME_DIR/containers/auth.json, if $XDG_RUNTIME_DIR is set
+//   - $HOME/.config/containers/auth.json
+//   - /run/containers/$UID/auth.json
+//
+// If none of them exists, the first location in that list is used.
+//
+// NewStoreFromPodman internally calls [NewStoreWithFallbacks]-friendly
+// [Store] implementations so it can be composed with [NewStoreFromDocker]
+// via [NewStoreWithFallbacks].
+//
+// References:
+//   - https://github.com/containers/image/blob/main/docs/containers-auth.json.5.md
+func NewStoreFromPodman(opts StoreOptions) (Store, error) {
+	configPath, err := getPodmanAuthFilePath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SetFileLockTimeout(opts.FileLockTimeout)
+	return &podmanStore{
+		config:  cfg,
+		options: opts,
+	}, nil
+}
+
+// Get retrieves credentials from the store for the given server address,
+// trying the longest matching path prefix of serverAddress first.
+func (ps *podmanStore) Get(ctx context.Context, serverAddress string) (auth.Credential, error) {
+	return ps.getStore(serverAddress).Get(ctx, serverAddress)
+}
+
+// Put saves credentials into the store for the given server address.
+// Returns ErrPlaintextPutDisabled if native store is not available and
+// StoreOptions.AllowPlaintextPut is set to false.
+func (ps *podmanStore) Put(ctx context.Context, serverAddress string, cred auth.Credential) error {
+	return ps.getStore(serverAddress).Put(ctx, serverAddress, cred)
+}
+
+// Delete removes credentials from the store for the given server address.
+func (ps *podmanStore) Delete(ctx context.Context, serverAddress string) error {
+	return ps.getStore(serverAddress).Delete(ctx, serverAddress)
+}
+
+// List lists the server addresses that have credentials stored in the
+// auth.json file.
+func (ps *podmanStore) List(ctx context.Context) (map[string]string, error) {
+	fs := newFileStore(ps.config)
+	return fs.List(ctx)
+}
+
+// getHelperSuffix returns the credential helper suffix for the longest
+// matching path prefix of the given server address. If
+// options.ServerAddressMatcher is set, it is consulted for every
+// configured credHelpers key and the longest matching key wins; otherwise
+// config.Config.GetCredentialHelper's default longest namespace-prefix
+// matching is used.
+func (ps *podmanStore) getHelperSuffix(serverAddress string) string {
+	matcher := ps.options.ServerAddressMatcher
+	if matcher == nil {
+		return ps.config.GetCredentialHelper(serverAddress)
+	}
+
+	var bestAddress, bestHelper string
+	for configuredAddress, helper := range ps.config.CredentialHelpers() {
+		if !matcher(configuredAddress, serverAddress) {
+			continue
+		}
+		if len(configuredAddress) > len(bestAddress) {
+			bestAddress, bestHelper = configuredAddress, helper
+		}
+	}
+	return bestHelper
+}
+
+// getStore returns a store for the given server address.
+func (ps *podmanStore) getStore(serverAddress string) Store {
+	if helper := ps.getHelperSuffix(serverAddress); helper != "" {
+		return NewNativeStore(helper)
+	}
+
+	fs := newFileStore(ps.config)
+	fs.DisablePut = !ps.options.AllowPlaintextPut
+	return &namespacedFileStore{FileStore: fs, config: ps.config}
+}
+
+// namespacedFileStore wraps FileStore so that Get() tries the longest
+// matching path prefix of the server address before falling back to the
+// bare host, mirroring auth.json's namespaced entries.
+type namespacedFileStore struct {
+	*FileStore
+	config *config.Config
+}
+
+// Get retrieves credentials from the store, trying the longest matching
+// path prefix of serverAddress first.
+func (nfs *namespacedFileStore) Get(ctx context.Context, serverAddress string) (auth.Credential, error) {
+	for _, candidate := range config.PrefixCandidates(config.NormalizeServerAddress(serverAddress)) {
+		cred, err := nfs.config.GetCredential(candidate)
+		if err != nil {
+			return auth.EmptyCredential, err
+		}
+		if cred != auth.EmptyCredential {
+			return cred, nil
+		}
+	}
+	return auth.EmptyCredential, nil
+}
+
+// getPodmanAuthFilePath returns the path to the Podman/Skopeo/Buildah
+// auth.json file, searching the standard locations in order and falling
+// back to the $XDG_RUNTIME_DIR location (or the home directory location, if
+// $XDG_RUNTIME_DIR is unset) if none of them exist yet.
+func getPodmanAuthFilePath() (string, error) {
+	searchPaths := podmanAuthFileSearchPaths()
+	for _, path := range searchPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return searchPaths[0], nil
+}
+
+// podmanAuthFileSearchPaths returns the standard search paths for the
+// auth.json file, in order. The /run/containers/$UID location is always
+// included as the final fallback, so the returned slice is never empty.
+func podmanAuthFileSearchPaths() []string {
+	var paths []string
+	if xdgRuntimeDir := os.Getenv(xdgRuntimeDirEnv); xdgRuntimeDir != "" {
+		paths = append(paths, filepath.Join(xdgRuntimeDir, containersConfigDir, containersAuthFileName))
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(homeDir, ".config", containersConfigDir, containersAuthFileName))
+	}
+	paths = append(paths, filepath.Join("/run", containersConfigDir, strconv.Itoa(os.Getuid()), containersAuthFileName))
+	return paths
+}
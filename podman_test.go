@@ -0,0 +1,180 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oras-project/oras-credentials-go/internal/config"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func newTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.Load(filepath.Join(t.TempDir(), "auth.json"))
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	return cfg
+}
+
+func TestNamespacedFileStoreGetLongestPrefixMatch(t *testing.T) {
+	cfg := newTestConfig(t)
+	fs := &namespacedFileStore{FileStore: newFileStore(cfg), config: cfg}
+	ctx := context.Background()
+
+	orgCred := auth.Credential{Username: "org-user"}
+	hostCred := auth.Credential{Username: "host-user"}
+	if err := cfg.PutCredential("ghcr.io/myorg", orgCred); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+	if err := cfg.PutCredential("ghcr.io", hostCred); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		serverAddress string
+		want          auth.Credential
+	}{
+		{"repo under the namespaced org falls back to the org entry", "ghcr.io/myorg/repo", orgCred},
+		{"exact org match", "ghcr.io/myorg", orgCred},
+		{"other org falls back to the bare host", "ghcr.io/otherorg", hostCred},
+		{"bare host", "ghcr.io", hostCred},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fs.Get(ctx, tt.serverAddress)
+			if err != nil {
+				t.Fatalf("Get(%q) error = %v", tt.serverAddress, err)
+			}
+			if got != tt.want {
+				t.Errorf("Get(%q) = %+v, want %+v", tt.serverAddress, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamespacedFileStoreGetNoMatch(t *testing.T) {
+	cfg := newTestConfig(t)
+	fs := &namespacedFileStore{FileStore: newFileStore(cfg), config: cfg}
+
+	got, err := fs.Get(context.Background(), "unconfigured.example.com/myorg/repo")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != auth.EmptyCredential {
+		t.Errorf("Get() = %+v, want EmptyCredential", got)
+	}
+}
+
+func TestPodmanStoreGetStoreWithoutConfiguredHelper(t *testing.T) {
+	cfg := newTestConfig(t)
+	ps := &podmanStore{config: cfg, options: StoreOptions{AllowPlaintextPut: true}}
+
+	// no credHelpers entry for this namespace: falls back to the
+	// namespaced file store.
+	if _, ok := ps.getStore("ghcr.io/myorg").(*namespacedFileStore); !ok {
+		t.Errorf("getStore() without a configured helper should return a *namespacedFileStore")
+	}
+	if helper := ps.getHelperSuffix("ghcr.io/myorg"); helper != "" {
+		t.Fatalf("unexpected credential helper %q configured for a fresh config", helper)
+	}
+}
+
+func TestPodmanStoreGetHelperSuffixLongestPrefix(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "auth.json")
+	const content = `{
+		"credHelpers": {
+			"ghcr.io": "host-helper",
+			"ghcr.io/myorg": "org-helper"
+		}
+	}`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	ps := &podmanStore{config: cfg, options: StoreOptions{AllowPlaintextPut: true}}
+
+	tests := []struct {
+		serverAddress string
+		want          string
+	}{
+		{"ghcr.io/myorg/repo", "org-helper"},
+		{"ghcr.io/myorg", "org-helper"},
+		{"ghcr.io/otherorg", "host-helper"},
+		{"ghcr.io", "host-helper"},
+		{"unconfigured.example.com", ""},
+	}
+	for _, tt := range tests {
+		if got := ps.getHelperSuffix(tt.serverAddress); got != tt.want {
+			t.Errorf("getHelperSuffix(%q) = %q, want %q", tt.serverAddress, got, tt.want)
+		}
+	}
+
+	if _, ok := ps.getStore("ghcr.io/myorg/repo").(*nativeStore); !ok {
+		t.Error("getStore() for a namespace with a configured helper should return a *nativeStore")
+	}
+}
+
+// TestPodmanStoreGetHelperSuffixUsesServerAddressMatcher asserts that
+// podmanStore.getHelperSuffix consults options.ServerAddressMatcher, rather
+// than falling through to the default longest namespace-prefix match, by
+// using a matcher that links two server addresses with no textual
+// relationship to each other.
+func TestPodmanStoreGetHelperSuffixUsesServerAddressMatcher(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "auth.json")
+	const content = `{
+		"credHelpers": {
+			"ghcr.io": "host-helper",
+			"ghcr.io/myorg": "org-helper"
+		}
+	}`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+
+	// aliasMatcher treats "alias.example.com" as an alias of "ghcr.io/myorg"
+	// and nothing else: a relationship the default longest-prefix matcher
+	// would never establish, so a non-empty result below can only come from
+	// the matcher being consulted.
+	aliasMatcher := func(configuredAddress, serverAddress string) bool {
+		return configuredAddress == "ghcr.io/myorg" && serverAddress == "alias.example.com"
+	}
+	ps := &podmanStore{config: cfg, options: StoreOptions{ServerAddressMatcher: aliasMatcher}}
+
+	if got := ps.getHelperSuffix("alias.example.com"); got != "org-helper" {
+		t.Errorf("getHelperSuffix() = %q, want %q", got, "org-helper")
+	}
+	if got := ps.getHelperSuffix("ghcr.io"); got != "" {
+		t.Errorf("getHelperSuffix() = %q, want \"\" (matcher does not link ghcr.io to any configured address)", got)
+	}
+
+	psNoMatcher := &podmanStore{config: cfg}
+	if got := psNoMatcher.getHelperSuffix("ghcr.io/myorg/repo"); got != "org-helper" {
+		t.Errorf("getHelperSuffix() without a matcher = %q, want default longest-prefix match %q", got, "org-helper")
+	}
+}
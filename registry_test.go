@@ -0,0 +1,143 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestCredentialMapsRegistry1DockerIO(t *testing.T) {
+	want := auth.Credential{Username: "user", Password: "pass"}
+	store := newMemoryStore(map[string]auth.Credential{
+		"https://index.docker.io/v1/": want,
+	})
+
+	got, err := Credential(store)(context.Background(), "registry-1.docker.io")
+	if err != nil {
+		t.Fatalf("Credential()() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Credential()() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCredentialEmptyHostnameShortCircuits(t *testing.T) {
+	store := errorStore{err: errors.New("store.Get should not be called for an empty hostname")}
+
+	got, err := Credential(store)(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Credential()() error = %v, want nil (should not reach the store)", err)
+	}
+	if got != auth.EmptyCredential {
+		t.Errorf("Credential()() = %+v, want EmptyCredential", got)
+	}
+}
+
+// fakeClient is a remote.Client that is not an *auth.Client, used to
+// exercise Login's rejection of unsupported client types.
+type fakeClient struct{}
+
+func (fakeClient) Do(*http.Request) (*http.Response, error) {
+	return nil, errors.New("fakeClient should not be called")
+}
+
+func newPingableRegistry(t *testing.T, v2Implemented bool) *remote.Registry {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v2/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if v2Implemented {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	uri, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid test http server: %v", err)
+	}
+	reg, err := remote.NewRegistry(uri.Host)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	reg.PlainHTTP = true
+	return reg
+}
+
+func TestLoginStoresCredentialOnSuccessfulPing(t *testing.T) {
+	reg := newPingableRegistry(t, true)
+	store := newMemoryStore(nil)
+	cred := auth.Credential{Username: "user", Password: "pass"}
+
+	if err := Login(context.Background(), store, reg, cred); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if got := store.creds[reg.Reference.Registry]; got != cred {
+		t.Errorf("store.creds[%q] = %+v, want %+v", reg.Reference.Registry, got, cred)
+	}
+}
+
+func TestLoginDoesNotStoreCredentialOnFailedPing(t *testing.T) {
+	reg := newPingableRegistry(t, false)
+	store := newMemoryStore(nil)
+	cred := auth.Credential{Username: "user", Password: "pass"}
+
+	if err := Login(context.Background(), store, reg, cred); err == nil {
+		t.Fatal("Login() error = nil, want non-nil for a registry that fails the ping check")
+	}
+	if len(store.creds) != 0 {
+		t.Errorf("store.creds = %v, want empty (Put must not be called when Ping fails)", store.creds)
+	}
+}
+
+func TestLoginRejectsUnsupportedClientType(t *testing.T) {
+	reg := newPingableRegistry(t, true)
+	reg.Client = fakeClient{}
+	store := newMemoryStore(nil)
+
+	err := Login(context.Background(), store, reg, auth.Credential{Username: "user"})
+	if !errors.Is(err, ErrClientTypeUnsupported) {
+		t.Errorf("Login() error = %v, want ErrClientTypeUnsupported", err)
+	}
+	if len(store.creds) != 0 {
+		t.Errorf("store.creds = %v, want empty", store.creds)
+	}
+}
+
+func TestLogoutMapsDockerIOToLegacyKey(t *testing.T) {
+	store := newMemoryStore(map[string]auth.Credential{
+		"https://index.docker.io/v1/": {Username: "user"},
+	})
+
+	if err := Logout(context.Background(), store, "docker.io"); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, ok := store.creds["https://index.docker.io/v1/"]; ok {
+		t.Error("Logout(\"docker.io\") did not delete the legacy index.docker.io key")
+	}
+}
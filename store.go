@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/oras-project/oras-credentials-go/internal/config"
 	"oras.land/oras-go/v2/registry/remote/auth"
@@ -42,6 +43,21 @@ type Store interface {
 	Delete(ctx context.Context, serverAddress string) error
 }
 
+// Lister is the interface that a credentials store can implement to
+// support enumerating the server addresses it has credentials for, mirroring
+// the `list` verb of the docker-credential-helper protocol.
+type Lister interface {
+	// List lists the server addresses that have credentials stored, mapped
+	// to their associated usernames.
+	List(ctx context.Context) (map[string]string, error)
+}
+
+// ServerAddressMatcher reports whether configuredAddress, a key of the
+// config file's credHelpers field, should be used as the credential helper
+// for serverAddress. It is used in place of the default longest
+// namespace-prefix matching when set on StoreOptions.
+type ServerAddressMatcher func(configuredAddress, serverAddress string) bool
+
 // dynamicStore dynamically determines which store to use based on the settings
 // in the config file.
 type dynamicStore struct {
@@ -60,6 +76,18 @@ type StoreOptions struct {
 	//   - If AllowPlaintextPut is set to true, Put() will save credentials in
 	//     plaintext in the config file when native store is not available.
 	AllowPlaintextPut bool
+
+	// FileLockTimeout is the duration to wait for the advisory lock on the
+	// config file before giving up.
+	//   - If FileLockTimeout is zero, a default timeout of 5 seconds is used.
+	//   - If the lock cannot be acquired within the timeout, ErrConfigLocked
+	//     is returned.
+	FileLockTimeout time.Duration
+
+	// ServerAddressMatcher, if set, overrides the default longest
+	// namespace-prefix matching used to resolve a server address's
+	// credential helper from the config file's credHelpers field.
+	ServerAddressMatcher ServerAddressMatcher
 }
 
 // NewStore returns a Store based on the given configuration file.
@@ -85,6 +113,7 @@ func NewStore(configPath string, opts StoreOptions) (Store, error) {
 	if err != nil {
 		return nil, err
 	}
+	cfg.SetFileLockTimeout(opts.FileLockTimeout)
 	ds := &dynamicStore{
 		config:  cfg,
 		options: opts,
@@ -142,11 +171,66 @@ func (ds *dynamicStore) Delete(ctx context.Context, serverAddress string) error
 	return ds.getStore(serverAddress).Delete(ctx, serverAddress)
 }
 
+// List lists the server addresses that have credentials stored across all
+// the configured credential helpers and the file store, merging and
+// deduplicating the results. When a server address is configured with more
+// than one backing store, the entry from the store that getStore would
+// choose for that address takes precedence.
+func (ds *dynamicStore) List(ctx context.Context) (map[string]string, error) {
+	result := make(map[string]string)
+
+	// 1. the file store, for server addresses with plain-text credentials
+	// or no specific helper configured.
+	fs := newFileStore(ds.config)
+	names, err := fs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials from the file store: %w", err)
+	}
+	filled := make(map[string]bool, len(names))
+	for serverAddress, username := range names {
+		result[serverAddress] = username
+		filled[serverAddress] = true
+	}
+
+	// 2. every native credential helper referenced by the config, including
+	// per-server helpers, the global credsStore, and the detected default.
+	helperSuffixes := make(map[string]struct{})
+	for _, helper := range ds.config.CredentialHelpers() {
+		helperSuffixes[helper] = struct{}{}
+	}
+	if credsStore := ds.config.CredentialsStore(); credsStore != "" {
+		helperSuffixes[credsStore] = struct{}{}
+	}
+	if ds.detectedCredsStore != "" {
+		helperSuffixes[ds.detectedCredsStore] = struct{}{}
+	}
+	for helper := range helperSuffixes {
+		lister, ok := newHelperStore(helper).(Lister)
+		if !ok {
+			continue
+		}
+		names, err := lister.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list credentials from native store %q: %w", helper, err)
+		}
+		for serverAddress, username := range names {
+			// prefer the entry from the store that getStore would choose
+			// for this server address.
+			if ds.getHelperSuffix(serverAddress) == helper || !filled[serverAddress] {
+				result[serverAddress] = username
+				filled[serverAddress] = true
+			}
+		}
+	}
+
+	return result, nil
+}
+
 // getHelperSuffix returns the credential helper suffix for the given server
 // address.
 func (ds *dynamicStore) getHelperSuffix(serverAddress string) string {
 	// 1. Look for a server-specific credential helper first
-	if helper := ds.config.GetCredentialHelper(serverAddress); helper != "" {
+	if helper := ds.getConfiguredHelper(serverAddress); helper != "" {
 		return helper
 	}
 	// 2. Then look for the configured native store
@@ -157,10 +241,33 @@ func (ds *dynamicStore) getHelperSuffix(serverAddress string) string {
 	return ds.detectedCredsStore
 }
 
+// getConfiguredHelper returns the credHelpers entry configured for
+// serverAddress. If options.ServerAddressMatcher is set, it is consulted
+// for every configured credHelpers key and the longest matching key wins;
+// otherwise config.Config.GetCredentialHelper's default longest
+// namespace-prefix matching is used.
+func (ds *dynamicStore) getConfiguredHelper(serverAddress string) string {
+	matcher := ds.options.ServerAddressMatcher
+	if matcher == nil {
+		return ds.config.GetCredentialHelper(serverAddress)
+	}
+
+	var bestAddress, bestHelper string
+	for configuredAddress, helper := range ds.config.CredentialHelpers() {
+		if !matcher(configuredAddress, serverAddress) {
+			continue
+		}
+		if len(configuredAddress) > len(bestAddress) {
+			bestAddress, bestHelper = configuredAddress, helper
+		}
+	}
+	return bestHelper
+}
+
 // getStore returns a store for the given server address.
 func (ds *dynamicStore) getStore(serverAddress string) Store {
 	if helper := ds.getHelperSuffix(serverAddress); helper != "" {
-		return NewNativeStore(helper)
+		return newHelperStore(helper)
 	}
 
 	fs := newFileStore(ds.config)
@@ -168,6 +275,17 @@ func (ds *dynamicStore) getStore(serverAddress string) Store {
 	return fs
 }
 
+// newHelperStore returns the Store for the given credsStore/credHelper
+// suffix, dispatching to the in-process system keychain store for
+// systemKeychainHelperSuffix and to an external docker-credential-<suffix>
+// helper binary otherwise.
+func newHelperStore(suffix string) Store {
+	if suffix == systemKeychainHelperSuffix {
+		return NewSystemKeychainStore()
+	}
+	return NewNativeStore(suffix)
+}
+
 // getDockerConfigPath returns the path to the default docker config file.
 func getDockerConfigPath() (string, error) {
 	// first try the environment variable
@@ -230,3 +348,26 @@ func (sf *storeWithFallbacks) Put(ctx context.Context, serverAddress string, cre
 func (sf *storeWithFallbacks) Delete(ctx context.Context, serverAddress string) error {
 	return sf.stores[0].Delete(ctx, serverAddress)
 }
+
+// List lists the server addresses that have credentials stored across the
+// primary and all the fallback stores, merging and deduplicating the
+// results. Stores that do not implement Lister are skipped.
+func (sf *storeWithFallbacks) List(ctx context.Context) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, s := range sf.stores {
+		lister, ok := s.(Lister)
+		if !ok {
+			continue
+		}
+		names, err := lister.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for serverAddress, username := range names {
+			if _, exists := result[serverAddress]; !exists {
+				result[serverAddress] = username
+			}
+		}
+	}
+	return result, nil
+}
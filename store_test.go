@@ -0,0 +1,303 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/oras-project/oras-credentials-go/internal/config"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// memoryStore is a minimal in-memory Store used to exercise
+// storeWithFallbacks without touching the filesystem or any native helper.
+type memoryStore struct {
+	creds map[string]auth.Credential
+}
+
+func newMemoryStore(creds map[string]auth.Credential) *memoryStore {
+	if creds == nil {
+		creds = make(map[string]auth.Credential)
+	}
+	return &memoryStore{creds: creds}
+}
+
+func (m *memoryStore) Get(_ context.Context, serverAddress string) (auth.Credential, error) {
+	if cred, ok := m.creds[serverAddress]; ok {
+		return cred, nil
+	}
+	return auth.EmptyCredential, nil
+}
+
+func (m *memoryStore) Put(_ context.Context, serverAddress string, cred auth.Credential) error {
+	m.creds[serverAddress] = cred
+	return nil
+}
+
+func (m *memoryStore) Delete(_ context.Context, serverAddress string) error {
+	delete(m.creds, serverAddress)
+	return nil
+}
+
+// memoryListStore is a memoryStore that also implements Lister.
+type memoryListStore struct {
+	*memoryStore
+}
+
+func (m *memoryListStore) List(_ context.Context) (map[string]string, error) {
+	names := make(map[string]string, len(m.creds))
+	for serverAddress, cred := range m.creds {
+		names[serverAddress] = cred.Username
+	}
+	return names, nil
+}
+
+func TestStoreWithFallbacksGet(t *testing.T) {
+	primary := newMemoryStore(nil)
+	fallback := newMemoryStore(map[string]auth.Credential{
+		"ghcr.io": {Username: "fallback-user"},
+	})
+	store := NewStoreWithFallbacks(primary, fallback)
+
+	cred, err := store.Get(context.Background(), "ghcr.io")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if cred.Username != "fallback-user" {
+		t.Errorf("Get().Username = %q, want %q", cred.Username, "fallback-user")
+	}
+}
+
+func TestStoreWithFallbacksPutDeleteUsePrimaryOnly(t *testing.T) {
+	primary := newMemoryStore(nil)
+	fallback := newMemoryStore(nil)
+	store := NewStoreWithFallbacks(primary, fallback)
+
+	cred := auth.Credential{Username: "user"}
+	if err := store.Put(context.Background(), "ghcr.io", cred); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, ok := primary.creds["ghcr.io"]; !ok {
+		t.Error("Put() did not write to the primary store")
+	}
+	if _, ok := fallback.creds["ghcr.io"]; ok {
+		t.Error("Put() wrote to the fallback store")
+	}
+
+	if err := store.Delete(context.Background(), "ghcr.io"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok := primary.creds["ghcr.io"]; ok {
+		t.Error("Delete() did not remove from the primary store")
+	}
+}
+
+func TestStoreWithFallbacksNoFallbacksReturnsPrimary(t *testing.T) {
+	primary := newMemoryStore(nil)
+	store := NewStoreWithFallbacks(primary)
+	if store != Store(primary) {
+		t.Error("NewStoreWithFallbacks with no fallbacks should return primary unchanged")
+	}
+}
+
+func TestStoreWithFallbacksList(t *testing.T) {
+	primary := &memoryListStore{newMemoryStore(map[string]auth.Credential{
+		"ghcr.io": {Username: "primary-user"},
+	})}
+	// a fallback that does not implement Lister must be skipped, not error.
+	nonLister := newMemoryStore(map[string]auth.Credential{
+		"docker.io": {Username: "should-not-appear"},
+	})
+	lister := &memoryListStore{newMemoryStore(map[string]auth.Credential{
+		"ghcr.io":     {Username: "shadowed-by-primary"},
+		"registry.io": {Username: "from-lister"},
+	})}
+	store := NewStoreWithFallbacks(primary, nonLister, lister)
+
+	lst, ok := store.(Lister)
+	if !ok {
+		t.Fatal("storeWithFallbacks must implement Lister")
+	}
+	names, err := lst.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := map[string]string{
+		"ghcr.io":     "primary-user",
+		"registry.io": "from-lister",
+	}
+	if len(names) != len(want) {
+		t.Fatalf("List() = %v, want %v", names, want)
+	}
+	for addr, username := range want {
+		if names[addr] != username {
+			t.Errorf("List()[%q] = %q, want %q", addr, names[addr], username)
+		}
+	}
+	if _, ok := names["docker.io"]; ok {
+		t.Error("List() included an entry from a non-Lister fallback store")
+	}
+}
+
+func TestStoreWithFallbacksGetPropagatesError(t *testing.T) {
+	erroringStore := errorStore{err: errors.New("boom")}
+	store := NewStoreWithFallbacks(newMemoryStore(nil), erroringStore)
+	if _, err := store.Get(context.Background(), "ghcr.io"); err == nil {
+		t.Error("Get() error = nil, want non-nil")
+	}
+}
+
+// errorStore is a Store whose Get always fails.
+type errorStore struct {
+	err error
+}
+
+func (e errorStore) Get(context.Context, string) (auth.Credential, error) {
+	return auth.EmptyCredential, e.err
+}
+
+func (e errorStore) Put(context.Context, string, auth.Credential) error {
+	return e.err
+}
+
+func (e errorStore) Delete(context.Context, string) error {
+	return e.err
+}
+
+// writeFakeNativeHelper writes an executable docker-credential-<suffix>
+// script to a new temp directory, returning that directory. The script
+// implements only the "list" verb of the docker-credential-helper protocol,
+// printing listing as its JSON response, so that newHelperStore(suffix) can
+// be exercised through a real helper binary without shelling out to an
+// actual native keychain.
+func writeFakeNativeHelper(t *testing.T, suffix string, listing map[string]string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake helper scripts are not supported on windows")
+	}
+
+	out, err := json.Marshal(listing)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	dir := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\ncase \"$1\" in\nlist) echo '%s' ;;\nesac\n", out)
+	path := filepath.Join(dir, remoteCredentialsPrefix+suffix)
+	if err := os.WriteFile(path, []byte(script), 0700); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return dir
+}
+
+// TestDynamicStoreListMergesFileStoreAndHelperEntries builds a dynamicStore
+// whose config configures a per-address credential helper for "ghcr.io" and
+// has plain-text credentials for both "ghcr.io" and an unrelated address,
+// then asserts that List() merges the file store and the helper: the helper
+// entry wins for "ghcr.io" (the address getHelperSuffix routes to that
+// helper), the file-only address survives unshadowed, and a helper-only
+// address not present in the file store is included too.
+func TestDynamicStoreListMergesFileStoreAndHelperEntries(t *testing.T) {
+	const helperSuffix = "testhelper"
+	helperDir := writeFakeNativeHelper(t, helperSuffix, map[string]string{
+		"ghcr.io":            "helper-user",
+		"shared.example.com": "helper-only-user",
+	})
+	t.Setenv("PATH", helperDir)
+
+	configPath := filepath.Join(t.TempDir(), "auth.json")
+	content := fmt.Sprintf(`{"credHelpers": {"ghcr.io": %q}}`, helperSuffix)
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	if err := cfg.PutCredential("ghcr.io", auth.Credential{Username: "file-user"}); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+	if err := cfg.PutCredential("file-only.example.com", auth.Credential{Username: "file-only-user"}); err != nil {
+		t.Fatalf("PutCredential() error = %v", err)
+	}
+
+	ds := &dynamicStore{config: cfg, options: StoreOptions{AllowPlaintextPut: true}}
+	got, err := ds.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := map[string]string{
+		"ghcr.io":               "helper-user",
+		"file-only.example.com": "file-only-user",
+		"shared.example.com":    "helper-only-user",
+	}
+	for addr, username := range want {
+		if got[addr] != username {
+			t.Errorf("List()[%q] = %q, want %q", addr, got[addr], username)
+		}
+	}
+}
+
+// TestDynamicStoreGetConfiguredHelperUsesServerAddressMatcher asserts that
+// getConfiguredHelper consults options.ServerAddressMatcher, rather than
+// falling through to the default longest namespace-prefix match, by using a
+// matcher that links two server addresses with no textual relationship to
+// each other.
+func TestDynamicStoreGetConfiguredHelperUsesServerAddressMatcher(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "auth.json")
+	const content = `{
+		"credHelpers": {
+			"ghcr.io": "host-helper",
+			"ghcr.io/myorg": "org-helper"
+		}
+	}`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+
+	// aliasMatcher treats "alias.example.com" as an alias of "ghcr.io/myorg"
+	// and nothing else: a relationship the default longest-prefix matcher
+	// would never establish, so a non-empty result below can only come from
+	// the matcher being consulted.
+	aliasMatcher := func(configuredAddress, serverAddress string) bool {
+		return configuredAddress == "ghcr.io/myorg" && serverAddress == "alias.example.com"
+	}
+	ds := &dynamicStore{config: cfg, options: StoreOptions{ServerAddressMatcher: aliasMatcher}}
+
+	if got := ds.getConfiguredHelper("alias.example.com"); got != "org-helper" {
+		t.Errorf("getConfiguredHelper() = %q, want %q", got, "org-helper")
+	}
+	if got := ds.getConfiguredHelper("ghcr.io"); got != "" {
+		t.Errorf("getConfiguredHelper() = %q, want \"\" (matcher does not link ghcr.io to any configured address)", got)
+	}
+
+	dsNoMatcher := &dynamicStore{config: cfg}
+	if got := dsNoMatcher.getConfiguredHelper("ghcr.io/myorg/repo"); got != "org-helper" {
+		t.Errorf("getConfiguredHelper() without a matcher = %q, want default longest-prefix match %q", got, "org-helper")
+	}
+}
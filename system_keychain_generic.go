@@ -0,0 +1,48 @@
+//go:build !windows && !darwin && !linux
+
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import "github.com/docker/docker-credential-helpers/credentials"
+
+// unavailableKeychainHelper is a credentials.Helper that always returns
+// ErrKeychainUnavailable. It is used on platforms with no native keychain
+// binding.
+type unavailableKeychainHelper struct{}
+
+func (unavailableKeychainHelper) Add(*credentials.Credentials) error {
+	return ErrKeychainUnavailable
+}
+
+func (unavailableKeychainHelper) Delete(string) error {
+	return ErrKeychainUnavailable
+}
+
+func (unavailableKeychainHelper) Get(string) (string, string, error) {
+	return "", "", ErrKeychainUnavailable
+}
+
+func (unavailableKeychainHelper) List() (map[string]string, error) {
+	return nil, ErrKeychainUnavailable
+}
+
+// newPlatformKeychainHelper returns a credentials.Helper that always fails
+// with ErrKeychainUnavailable, since this platform has no native keychain
+// binding.
+func newPlatformKeychainHelper() credentials.Helper {
+	return unavailableKeychainHelper{}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker-credential-helpers/credentials"
+
+	"github.com/oras-project/oras-credentials-go/internal/secretservice"
+)
+
+// secretServiceAttributeServer is the Secret Service item attribute used
+// to key entries by server address.
+const secretServiceAttributeServer = "server"
+
+// secretServiceLabel is the prefix of the human-readable label given to
+// items created in the keyring, shown by keyring UIs such as Seahorse; the
+// full label is "<secretServiceLabel>: <server address>".
+const secretServiceLabel = "oras-credentials-go credentials"
+
+// secretServicePayload is the JSON-encoded Secret Service secret value,
+// since a single Secret Service item only holds one opaque secret.
+type secretServicePayload struct {
+	Username string `json:"username"`
+	Secret   string `json:"secret"`
+}
+
+// secretServiceHelper implements credentials.Helper using the Secret
+// Service D-Bus API (org.freedesktop.secrets), without cgo or a dependency
+// on libsecret. Its methods return secretservice.ErrNoSessionBus if no
+// D-Bus session bus is available.
+type secretServiceHelper struct{}
+
+// Add creates or replaces the keyring item for creds.ServerURL.
+func (secretServiceHelper) Add(creds *credentials.Credentials) error {
+	client, err := secretservice.New()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	value, err := json.Marshal(secretServicePayload{Username: creds.Username, Secret: creds.Secret})
+	if err != nil {
+		return fmt.Errorf("failed to encode secret item: %w", err)
+	}
+	attributes := map[string]string{secretServiceAttributeServer: creds.ServerURL}
+	return client.Set(secretServiceLabel+": "+creds.ServerURL, attributes, value)
+}
+
+// Delete removes the keyring item for serverURL.
+func (secretServiceHelper) Delete(serverURL string) error {
+	client, err := secretservice.New()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.Delete(map[string]string{secretServiceAttributeServer: serverURL})
+}
+
+// Get retrieves the username and secret stored for serverURL.
+func (secretServiceHelper) Get(serverURL string) (string, string, error) {
+	client, err := secretservice.New()
+	if err != nil {
+		return "", "", err
+	}
+	defer client.Close()
+
+	value, err := client.Get(map[string]string{secretServiceAttributeServer: serverURL})
+	if err != nil {
+		return "", "", err
+	}
+	if value == nil {
+		return "", "", credentials.NewErrCredentialsNotFound()
+	}
+	var payload secretServicePayload
+	if err := json.Unmarshal(value, &payload); err != nil {
+		return "", "", fmt.Errorf("failed to decode secret item: %w", err)
+	}
+	return payload.Username, payload.Secret, nil
+}
+
+// List returns the server addresses stored in the keyring, mapped to their
+// associated usernames.
+func (secretServiceHelper) List() (map[string]string, error) {
+	client, err := secretservice.New()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	allAttributes, err := client.List()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(allAttributes))
+	for _, attributes := range allAttributes {
+		serverURL, ok := attributes[secretServiceAttributeServer]
+		if !ok {
+			continue
+		}
+		value, err := client.Get(attributes)
+		if err != nil || value == nil {
+			continue
+		}
+		var payload secretServicePayload
+		if err := json.Unmarshal(value, &payload); err != nil {
+			continue
+		}
+		result[serverURL] = payload.Username
+	}
+	return result, nil
+}
+
+// newPlatformKeychainHelper returns a credentials.Helper backed by the
+// Secret Service D-Bus API.
+func newPlatformKeychainHelper() credentials.Helper {
+	return secretServiceHelper{}
+}
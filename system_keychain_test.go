@@ -0,0 +1,161 @@
+/*
+Copyright The ORAS Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker-credential-helpers/credentials"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// fakeKeychainHelper is an in-memory credentials.Helper used to test
+// systemKeychainStore without touching a real platform keychain.
+type fakeKeychainHelper struct {
+	creds map[string]*credentials.Credentials
+}
+
+func newFakeKeychainHelper() *fakeKeychainHelper {
+	return &fakeKeychainHelper{creds: make(map[string]*credentials.Credentials)}
+}
+
+func (f *fakeKeychainHelper) Add(creds *credentials.Credentials) error {
+	f.creds[creds.ServerURL] = creds
+	return nil
+}
+
+func (f *fakeKeychainHelper) Delete(serverURL string) error {
+	delete(f.creds, serverURL)
+	return nil
+}
+
+func (f *fakeKeychainHelper) Get(serverURL string) (string, string, error) {
+	creds, ok := f.creds[serverURL]
+	if !ok {
+		return "", "", credentials.NewErrCredentialsNotFound()
+	}
+	return creds.Username, creds.Secret, nil
+}
+
+func (f *fakeKeychainHelper) List() (map[string]string, error) {
+	names := make(map[string]string, len(f.creds))
+	for serverURL, creds := range f.creds {
+		names[serverURL] = creds.Username
+	}
+	return names, nil
+}
+
+func TestSystemKeychainStorePutGet(t *testing.T) {
+	store := &systemKeychainStore{helper: newFakeKeychainHelper()}
+	ctx := context.Background()
+
+	want := auth.Credential{Username: "user", Password: "pass"}
+	if err := store.Put(ctx, "ghcr.io", want); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	got, err := store.Get(ctx, "ghcr.io")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSystemKeychainStoreBearerTokenUsesSentinelUsername(t *testing.T) {
+	helper := newFakeKeychainHelper()
+	store := &systemKeychainStore{helper: helper}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "ghcr.io", auth.Credential{RefreshToken: "refresh-token"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if helper.creds["ghcr.io"].Username != emptyUsername {
+		t.Errorf("bearer auth stored username = %q, want sentinel %q", helper.creds["ghcr.io"].Username, emptyUsername)
+	}
+
+	got, err := store.Get(ctx, "ghcr.io")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.RefreshToken != "refresh-token" {
+		t.Errorf("Get().RefreshToken = %q, want %q", got.RefreshToken, "refresh-token")
+	}
+}
+
+func TestSystemKeychainStoreGetNotFoundReturnsEmptyCredential(t *testing.T) {
+	store := &systemKeychainStore{helper: newFakeKeychainHelper()}
+	got, err := store.Get(context.Background(), "ghcr.io")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != auth.EmptyCredential {
+		t.Errorf("Get() = %+v, want EmptyCredential", got)
+	}
+}
+
+// erroringKeychainHelper is a credentials.Helper whose every method fails,
+// simulating a platform with no native keychain binding.
+type erroringKeychainHelper struct{}
+
+func (erroringKeychainHelper) Add(*credentials.Credentials) error { return ErrKeychainUnavailable }
+func (erroringKeychainHelper) Delete(string) error                { return ErrKeychainUnavailable }
+func (erroringKeychainHelper) Get(string) (string, string, error) {
+	return "", "", ErrKeychainUnavailable
+}
+func (erroringKeychainHelper) List() (map[string]string, error) {
+	return nil, ErrKeychainUnavailable
+}
+
+func TestSystemKeychainStoreUnavailable(t *testing.T) {
+	store := &systemKeychainStore{helper: erroringKeychainHelper{}}
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "ghcr.io"); !errors.Is(err, ErrKeychainUnavailable) {
+		t.Errorf("Get() error = %v, want ErrKeychainUnavailable", err)
+	}
+	if err := store.Put(ctx, "ghcr.io", auth.Credential{Username: "user"}); !errors.Is(err, ErrKeychainUnavailable) {
+		t.Errorf("Put() error = %v, want ErrKeychainUnavailable", err)
+	}
+	if err := store.Delete(ctx, "ghcr.io"); !errors.Is(err, ErrKeychainUnavailable) {
+		t.Errorf("Delete() error = %v, want ErrKeychainUnavailable", err)
+	}
+	if _, err := store.List(ctx); !errors.Is(err, ErrKeychainUnavailable) {
+		t.Errorf("List() error = %v, want ErrKeychainUnavailable", err)
+	}
+}
+
+func TestNewHelperStoreDispatchesSystemKeychainSuffix(t *testing.T) {
+	if _, ok := newHelperStore(systemKeychainHelperSuffix).(*systemKeychainStore); !ok {
+		t.Errorf("newHelperStore(%q) did not return a *systemKeychainStore", systemKeychainHelperSuffix)
+	}
+	if _, ok := newHelperStore("desktop").(*nativeStore); !ok {
+		t.Error("newHelperStore(\"desktop\") did not return a *nativeStore")
+	}
+}
+
+func TestGetDefaultHelperSuffixFallsBackToSystemKeychainWithoutHelperBinary(t *testing.T) {
+	// with no docker-credential-<platform> binary reachable on PATH, the
+	// default store must fall back to the in-process system keychain
+	// rather than a helper binary that cannot be found.
+	t.Setenv("PATH", t.TempDir())
+
+	if got := getDefaultHelperSuffix(); got != systemKeychainHelperSuffix {
+		t.Errorf("getDefaultHelperSuffix() = %q, want %q", got, systemKeychainHelperSuffix)
+	}
+}